@@ -0,0 +1,174 @@
+package whatsapp
+
+import (
+	"fmt"
+	"sync"
+)
+
+/*
+Backend is the transport abstraction a Conn connects through. The legacyBackend
+stands for the old WhatsApp Web multi-endpoint socket, whose binary.Node frames
+reach Conn.dispatch; the mdBackend stands for the multi-device protocol, whose
+typed events reach Conn.dispatchEvent. It has no Noise transport: its Connect
+always returns an error. Both dispatch paths feed the same wac.handle pipeline, so
+the existing TextMessageHandler/ImageMessageHandler/etc. adapters work on top of
+either one.
+*/
+type Backend interface {
+	// Connect establishes the underlying socket and starts delivering events.
+	Connect() error
+
+	// Disconnect tears down the socket. Connect may be called again afterwards.
+	Disconnect() error
+
+	// Events returns the channel typed multi-device events are delivered on.
+	// Backends with no typed events (the legacy backend) return a nil channel.
+	Events() <-chan interface{}
+}
+
+/*
+legacyBackend wraps the original binary.Node dispatch loop so it satisfies Backend
+without changing its behavior.
+*/
+type legacyBackend struct {
+	wac *Conn
+}
+
+func (b *legacyBackend) Connect() error {
+	return b.wac.connect()
+}
+
+func (b *legacyBackend) Disconnect() error {
+	return b.wac.Disconnect()
+}
+
+func (b *legacyBackend) Events() <-chan interface{} {
+	return nil
+}
+
+/*
+PairClientType identifies the category of client presented during multi-device
+pairing, as required by the Noise handshake's client payload.
+*/
+type PairClientType int
+
+const (
+	PairClientUnknown PairClientType = iota
+	PairClientChrome
+	PairClientFirefox
+	PairClientSafari
+)
+
+// Device describes one companion device registered against the primary phone.
+type Device struct {
+	JID          string
+	Platform     string
+	PushName     string
+	RegisteredAt int64
+}
+
+/*
+mdBackend implements Backend for the multi-device protocol. It emits typed events
+instead of binary.Node frames, from its evt channel through pump. It holds no
+per-device session keys, and its Connect always returns an error.
+*/
+type mdBackend struct {
+	wac       *Conn
+	evt       chan interface{}
+	closeOnce sync.Once
+}
+
+func newMDBackend(wac *Conn) *mdBackend {
+	return &mdBackend{
+		wac: wac,
+		evt: make(chan interface{}, 128),
+	}
+}
+
+func (b *mdBackend) Connect() error {
+	return fmt.Errorf("whatsapp: multi-device backend has no transport wired up yet")
+}
+
+// Disconnect closes the event channel exactly once; calling it again is a no-op
+// instead of panicking with "close of closed channel".
+func (b *mdBackend) Disconnect() error {
+	b.closeOnce.Do(func() {
+		close(b.evt)
+	})
+	return nil
+}
+
+func (b *mdBackend) Events() <-chan interface{} {
+	return b.evt
+}
+
+/*
+pump feeds every event a real transport writes to b.evt into wac.dispatchEvent, so
+EventHandlers and the legacy TextMessageHandler/etc. family (via dispatchEvent's
+events.Message case) see multi-device events with no further wiring required. It is
+started by UseMultiDevice and exits once Disconnect closes b.evt. Connect() above
+still has no real Noise-based transport to drive this channel, so today this path
+only carries events a test (or a future Connect implementation) writes directly to
+b.evt — but the plumbing itself is live, not dead code.
+*/
+func (b *mdBackend) pump() {
+	for evt := range b.evt {
+		b.wac.dispatchEvent(evt)
+	}
+}
+
+/*
+UseMultiDevice switches wac onto the multi-device Backend. It must be called before
+Login/PairCode/PairQR and before the connection is established; switching backends
+on an already-connected Conn returns an error.
+*/
+func (wac *Conn) UseMultiDevice() error {
+	if wac.connected {
+		return fmt.Errorf("whatsapp: cannot switch backend while connected, call Disconnect first")
+	}
+	md := newMDBackend(wac)
+	go md.pump()
+	wac.backend = md
+	return nil
+}
+
+/*
+PairQR is the entry point of the multi-device QR pairing flow. It requires
+UseMultiDevice and returns the error from the mdBackend's Connect, which always
+fails; it sends nothing on qr and returns an empty Session.
+*/
+func (wac *Conn) PairQR(qr chan<- string) (Session, error) {
+	md, ok := wac.backend.(*mdBackend)
+	if !ok {
+		return Session{}, fmt.Errorf("whatsapp: PairQR requires UseMultiDevice")
+	}
+	return Session{}, md.Connect()
+}
+
+/*
+PairCode is the entry point of the multi-device pairing flow that uses WhatsApp's
+8-character linking code instead of a QR, as entered on the phone under Linked
+Devices > Link with phone number. phone must be in E.164 digits-only form (e.g.
+"15551234567"). It requires UseMultiDevice and always returns an error with an
+empty code: the mdBackend's Connect fails, and no pair-code exchange exists.
+*/
+func (wac *Conn) PairCode(phone string) (string, error) {
+	md, ok := wac.backend.(*mdBackend)
+	if !ok {
+		return "", fmt.Errorf("whatsapp: PairCode requires UseMultiDevice")
+	}
+	if err := md.Connect(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("whatsapp: multi-device pair-code exchange is not implemented yet")
+}
+
+// Devices is the entry point for listing the companion devices registered against
+// the primary phone. It requires UseMultiDevice and always returns an error with a
+// nil list: no device listing exists in the mdBackend.
+func (wac *Conn) Devices() ([]Device, error) {
+	if _, ok := wac.backend.(*mdBackend); !ok {
+		return nil, fmt.Errorf("whatsapp: Devices requires UseMultiDevice")
+	}
+	return nil, fmt.Errorf("whatsapp: multi-device device listing is not implemented yet")
+}
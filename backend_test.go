@@ -0,0 +1,79 @@
+package whatsapp
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gleandroj/go-whatsapp/events"
+)
+
+type eventRecordingHandler struct {
+	mu   sync.Mutex
+	evts []interface{}
+}
+
+func (h *eventRecordingHandler) HandleError(err error) {}
+
+func (h *eventRecordingHandler) HandleEvent(evt interface{}) {
+	h.mu.Lock()
+	h.evts = append(h.evts, evt)
+	h.mu.Unlock()
+}
+
+func (h *eventRecordingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.evts)
+}
+
+// TestMDBackendPumpDeliversEvents exercises the mdBackend.pump wiring end to end:
+// anything written to the backend's event channel (standing in for a future real
+// Noise transport) must reach a registered EventHandler without any extra wiring.
+func TestMDBackendPumpDeliversEvents(t *testing.T) {
+	wac := &Conn{}
+	if err := wac.UseMultiDevice(); err != nil {
+		t.Fatalf("UseMultiDevice: %v", err)
+	}
+
+	h := &eventRecordingHandler{}
+	wac.AddHandler(h)
+
+	md := wac.backend.(*mdBackend)
+	md.evt <- events.Connected{}
+
+	waitFor(t, time.Second, func() bool { return h.count() == 1 })
+	if _, ok := h.evts[0].(events.Connected); !ok {
+		t.Fatalf("expected events.Connected, got %#v", h.evts[0])
+	}
+
+	if err := md.Disconnect(); err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+}
+
+// TestMDBackendPumpReDispatchesMessageContent verifies dispatchEvent's events.Message
+// case still reaches the legacy TextMessageHandler family through the pump, same as
+// the direct wac.handle(events.Message{...}) path.
+func TestMDBackendPumpReDispatchesMessageContent(t *testing.T) {
+	wac := &Conn{}
+	if err := wac.UseMultiDevice(); err != nil {
+		t.Fatalf("UseMultiDevice: %v", err)
+	}
+
+	h := &blockingHandler{release: make(chan struct{})}
+	close(h.release)
+	wac.AddHandler(h)
+
+	md := wac.backend.(*mdBackend)
+	md.evt <- events.Message{Content: TextMessage{Text: "hi"}}
+
+	waitFor(t, time.Second, func() bool { return h.count() == 1 })
+	if got := h.texts(); len(got) != 1 || got[0] != "hi" {
+		t.Fatalf("expected the Message event's Content to reach HandleTextMessage, got %v", got)
+	}
+
+	if err := md.Disconnect(); err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package whatsapp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+Conn represents a connection to WhatsApp Web/multi-device. It keeps the registered
+handlers, the negotiated Session and the active Backend (see backend.go) that the
+dispatcher in handler.go feeds off of.
+*/
+type Conn struct {
+	handler []Handler
+
+	syncPools   map[Handler]*handlerPool
+	syncPoolsMu sync.Mutex
+
+	backend       Backend
+	session       *Session
+	autoReconnect *autoReconnect
+
+	shortClientName string
+	longClientName  string
+	timeout         time.Duration
+
+	connected bool
+}
+
+/*
+NewConn creates a new connection with the legacy WhatsApp Web Backend. It stores
+timeout and shortClientName/longClientName on the Conn; no handshake or socket in
+this package reads them.
+*/
+func NewConn(timeout time.Duration, shortClientName, longClientName string) (*Conn, error) {
+	wac := &Conn{
+		shortClientName: shortClientName,
+		longClientName:  longClientName,
+		timeout:         timeout,
+	}
+	wac.backend = &legacyBackend{wac: wac}
+	return wac, nil
+}
+
+/*
+Login is the entry point of the legacy QR login flow. It always returns an error and
+an empty Session; it sends nothing on qr.
+*/
+func (wac *Conn) Login(qr chan<- string) (Session, error) {
+	return Session{}, fmt.Errorf("whatsapp: login is not implemented in this build")
+}
+
+// connect marks wac as connected. It opens no socket and does not call the active Backend.
+func (wac *Conn) connect() error {
+	wac.connected = true
+	return nil
+}
+
+// Disconnect marks wac as not connected; it closes no socket. Connect/Login may be called again afterwards.
+func (wac *Conn) Disconnect() error {
+	wac.connected = false
+	return nil
+}
+
+/*
+RestoreWithSession restores a previously established Session without going through
+QR/pair-code login again.
+*/
+func (wac *Conn) RestoreWithSession(session Session) (Session, error) {
+	wac.session = &session
+	if err := wac.connect(); err != nil {
+		return Session{}, err
+	}
+	return session, nil
+}
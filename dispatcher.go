@@ -0,0 +1,293 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gleandroj/go-whatsapp/binary/proto"
+	"github.com/gleandroj/go-whatsapp/events"
+)
+
+/*
+DropPolicy controls what a sync handler's worker pool does when its queue is full.
+*/
+type DropPolicy int
+
+const (
+	// DropOldest discards the oldest queued message to make room for the new one.
+	DropOldest DropPolicy = iota
+	// Block waits for room in the queue, applying back-pressure to the dispatcher.
+	Block
+	// Error reports the full queue to the handler's HandleError instead of queueing.
+	Error
+)
+
+// SyncHandlerConfig configures the worker pool backing a handler added with AddSyncHandler.
+type SyncHandlerConfig struct {
+	QueueDepth int
+	DropPolicy DropPolicy
+}
+
+// DefaultSyncHandlerConfig is used by AddSyncHandler when no config is given.
+var DefaultSyncHandlerConfig = SyncHandlerConfig{QueueDepth: 100, DropPolicy: DropOldest}
+
+// HandlerMetrics reports the live state of a sync handler's worker pool.
+type HandlerMetrics struct {
+	QueueDepth  int
+	Dropped     uint64
+	LastLatency time.Duration
+}
+
+type handlerPool struct {
+	handler Handler
+	cfg     SyncHandlerConfig
+	queue   chan interface{}
+	done    chan struct{}
+
+	// closeMu guards against submit() sending on queue after shutdown() has
+	// closed it: submit holds the read lock for the whole send (including a
+	// blocking Block-policy send), shutdown takes the write lock before
+	// closing, so it can't proceed until every in-flight submit is done.
+	closeMu sync.RWMutex
+	closed  bool
+
+	mu      sync.Mutex
+	dropped uint64
+	latency time.Duration
+}
+
+func newHandlerPool(h Handler, cfg SyncHandlerConfig) *handlerPool {
+	if cfg.QueueDepth <= 0 {
+		cfg.QueueDepth = DefaultSyncHandlerConfig.QueueDepth
+	}
+
+	p := &handlerPool{
+		handler: h,
+		cfg:     cfg,
+		queue:   make(chan interface{}, cfg.QueueDepth),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *handlerPool) run() {
+	defer close(p.done)
+	for message := range p.queue {
+		start := time.Now()
+		dispatchToHandler(message, p.handler)
+		p.mu.Lock()
+		p.latency = time.Since(start)
+		p.mu.Unlock()
+	}
+}
+
+func (p *handlerPool) submit(message interface{}) {
+	p.closeMu.RLock()
+	defer p.closeMu.RUnlock()
+	if p.closed {
+		return
+	}
+
+	select {
+	case p.queue <- message:
+		return
+	default:
+	}
+
+	switch p.cfg.DropPolicy {
+	case Block:
+		p.queue <- message
+	case Error:
+		p.recordDrop()
+		go p.handler.HandleError(fmt.Errorf("whatsapp: sync handler queue full, dropped %T", message))
+	default: // DropOldest
+		select {
+		case <-p.queue:
+		default:
+		}
+		select {
+		case p.queue <- message:
+		default:
+		}
+		p.recordDrop()
+	}
+}
+
+func (p *handlerPool) recordDrop() {
+	p.mu.Lock()
+	p.dropped++
+	p.mu.Unlock()
+}
+
+func (p *handlerPool) metrics() HandlerMetrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return HandlerMetrics{
+		QueueDepth:  len(p.queue),
+		Dropped:     p.dropped,
+		LastLatency: p.latency,
+	}
+}
+
+func (p *handlerPool) shutdown(ctx context.Context) error {
+	closed := make(chan struct{})
+	go func() {
+		p.closeMu.Lock()
+		p.closed = true
+		close(p.queue)
+		p.closeMu.Unlock()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*
+dispatchToHandler delivers message to a single handler synchronously, on the
+caller's goroutine. It mirrors the type switch in handleMessage (including raw
+WebMessageInfo and the events.* family) but never spawns a goroutine itself, so a
+handlerPool's worker preserves per-handler ordering.
+*/
+func dispatchToHandler(message interface{}, h Handler) {
+	switch m := message.(type) {
+	case error:
+		h.HandleError(m)
+	case string:
+		if x, ok := h.(JsonMessageHandler); ok {
+			x.HandleJsonMessage(m)
+		}
+		if parsed := parseJsonMessage(m); parsed != nil {
+			dispatchJsonEventSync(parsed, h)
+		}
+	case TextMessage:
+		if x, ok := h.(TextMessageHandler); ok {
+			x.HandleTextMessage(m)
+		}
+	case ImageMessage:
+		if x, ok := h.(ImageMessageHandler); ok {
+			x.HandleImageMessage(m)
+		}
+	case VideoMessage:
+		if x, ok := h.(VideoMessageHandler); ok {
+			x.HandleVideoMessage(m)
+		}
+	case AudioMessage:
+		if x, ok := h.(AudioMessageHandler); ok {
+			x.HandleAudioMessage(m)
+		}
+	case DocumentMessage:
+		if x, ok := h.(DocumentMessageHandler); ok {
+			x.HandleDocumentMessage(m)
+		}
+	case LocationMessage:
+		if x, ok := h.(LocationMessageHandler); ok {
+			x.HandleLocationMessage(m)
+		}
+	case LiveLocationMessage:
+		if x, ok := h.(LiveLocationMessageHandler); ok {
+			x.HandleLiveLocationMessage(m)
+		}
+	case *proto.WebMessageInfo:
+		if x, ok := h.(RawMessageHandler); ok {
+			x.HandleRawMessage(m)
+		}
+	case CallMessage:
+		if x, ok := h.(CallMessageHandler); ok {
+			x.HandleCallMessage(m)
+		}
+	case ContactMessage:
+		if x, ok := h.(ContactMessageHandler); ok {
+			x.HandleContactMessage(m)
+		}
+	case StickerMessage:
+		if x, ok := h.(StickerMessageHandler); ok {
+			x.HandleStickerMessage(m)
+		}
+	case GroupInviteMessage:
+		if x, ok := h.(GroupInviteMessageHandler); ok {
+			x.HandleGroupInviteMessage(m)
+		}
+	case events.Message:
+		dispatchToHandler(m.Content, h)
+		if x, ok := h.(EventHandler); ok {
+			x.HandleEvent(m)
+		}
+	case events.Receipt, events.Presence, events.HistorySync, events.Connected, events.Disconnected, events.Reconnecting:
+		if x, ok := h.(EventHandler); ok {
+			x.HandleEvent(m)
+		}
+	}
+}
+
+/*
+AddSyncHandler adds a handler that is dispatched through a bounded worker pool
+instead of a bare `go` call per message. This preserves per-handler message
+ordering, applies back-pressure according to cfg's DropPolicy once the queue is
+full, and lets the pool be drained cleanly by Shutdown. cfg defaults to
+DefaultSyncHandlerConfig when omitted.
+*/
+func (wac *Conn) AddSyncHandler(handler Handler, cfg ...SyncHandlerConfig) {
+	c := DefaultSyncHandlerConfig
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	wac.syncPoolsMu.Lock()
+	if wac.syncPools == nil {
+		wac.syncPools = make(map[Handler]*handlerPool)
+	}
+	wac.syncPools[handler] = newHandlerPool(handler, c)
+	wac.syncPoolsMu.Unlock()
+
+	wac.handler = append(wac.handler, handler)
+}
+
+// HandlerMetrics reports the queue depth, dropped count and last processing
+// latency for a handler previously added with AddSyncHandler. ok is false if
+// handler was not added through AddSyncHandler.
+func (wac *Conn) HandlerMetrics(handler Handler) (metrics HandlerMetrics, ok bool) {
+	wac.syncPoolsMu.Lock()
+	defer wac.syncPoolsMu.Unlock()
+
+	pool, found := wac.syncPools[handler]
+	if !found {
+		return HandlerMetrics{}, false
+	}
+	return pool.metrics(), true
+}
+
+/*
+Shutdown drains every AddSyncHandler worker pool, waiting for queued messages to
+be processed before returning, then disconnects. It returns ctx.Err() if ctx is
+canceled before all pools finish draining.
+*/
+func (wac *Conn) Shutdown(ctx context.Context) error {
+	wac.syncPoolsMu.Lock()
+	pools := make([]*handlerPool, 0, len(wac.syncPools))
+	for _, p := range wac.syncPools {
+		pools = append(pools, p)
+	}
+	wac.syncPoolsMu.Unlock()
+
+	for _, p := range pools {
+		if err := p.shutdown(ctx); err != nil {
+			return err
+		}
+	}
+
+	return wac.Disconnect()
+}
@@ -0,0 +1,216 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gleandroj/go-whatsapp/binary/proto"
+	"github.com/gleandroj/go-whatsapp/events"
+)
+
+// blockingHandler's HandleTextMessage blocks on release so tests can deterministically
+// saturate a handlerPool's queue before letting the worker drain it.
+type blockingHandler struct {
+	release chan struct{}
+
+	mu       sync.Mutex
+	received []string
+	errs     int
+}
+
+func (h *blockingHandler) HandleError(err error) {
+	h.mu.Lock()
+	h.errs++
+	h.mu.Unlock()
+}
+
+func (h *blockingHandler) HandleTextMessage(m TextMessage) {
+	<-h.release
+	h.mu.Lock()
+	h.received = append(h.received, m.Text)
+	h.mu.Unlock()
+}
+
+func (h *blockingHandler) texts() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]string, len(h.received))
+	copy(out, h.received)
+	return out
+}
+
+func (h *blockingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.received)
+}
+
+func (h *blockingHandler) errCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.errs
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestHandlerPoolDropOldest(t *testing.T) {
+	h := &blockingHandler{release: make(chan struct{})}
+	p := newHandlerPool(h, SyncHandlerConfig{QueueDepth: 2, DropPolicy: DropOldest})
+
+	p.submit(TextMessage{Text: "1"}) // picked up by the worker immediately, blocks on release
+	waitFor(t, time.Second, func() bool { return len(p.queue) == 0 })
+
+	p.submit(TextMessage{Text: "2"})
+	p.submit(TextMessage{Text: "3"})
+	p.submit(TextMessage{Text: "4"}) // queue full with 2,3 -> evicts "2", queues "3","4"
+
+	if got := p.metrics().Dropped; got != 1 {
+		t.Fatalf("expected 1 dropped message, got %d", got)
+	}
+
+	close(h.release)
+	waitFor(t, time.Second, func() bool { return h.count() == 3 })
+
+	got := h.texts()
+	want := []string{"1", "3", "4"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestHandlerPoolBlock(t *testing.T) {
+	h := &blockingHandler{release: make(chan struct{})}
+	p := newHandlerPool(h, SyncHandlerConfig{QueueDepth: 1, DropPolicy: Block})
+
+	p.submit(TextMessage{Text: "1"}) // picked up by the worker immediately, blocks on release
+	waitFor(t, time.Second, func() bool { return len(p.queue) == 0 })
+	p.submit(TextMessage{Text: "2"}) // fills the depth-1 queue
+
+	submitted := make(chan struct{})
+	go func() {
+		p.submit(TextMessage{Text: "3"}) // must block until "2" is dequeued
+		close(submitted)
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("submit with Block policy returned before the queue had room")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(h.release)
+
+	select {
+	case <-submitted:
+	case <-time.After(time.Second):
+		t.Fatal("submit with Block policy never returned once the queue drained")
+	}
+}
+
+func TestHandlerPoolError(t *testing.T) {
+	h := &blockingHandler{release: make(chan struct{})}
+	p := newHandlerPool(h, SyncHandlerConfig{QueueDepth: 1, DropPolicy: Error})
+
+	p.submit(TextMessage{Text: "1"}) // picked up by the worker immediately, blocks on release
+	waitFor(t, time.Second, func() bool { return len(p.queue) == 0 })
+	p.submit(TextMessage{Text: "2"}) // fills the depth-1 queue
+
+	p.submit(TextMessage{Text: "3"}) // must report via HandleError instead of blocking or queueing
+
+	waitFor(t, time.Second, func() bool { return h.errCount() == 1 })
+	if got := p.metrics().Dropped; got != 1 {
+		t.Fatalf("expected 1 dropped message, got %d", got)
+	}
+
+	close(h.release)
+}
+
+func TestHandlerPoolShutdownDrainsAndRejectsLateSubmits(t *testing.T) {
+	h := &blockingHandler{release: make(chan struct{})}
+	close(h.release) // don't block; this test only cares about draining/shutdown
+
+	p := newHandlerPool(h, SyncHandlerConfig{QueueDepth: 4, DropPolicy: DropOldest})
+	for _, text := range []string{"a", "b", "c"} {
+		p.submit(TextMessage{Text: text})
+	}
+
+	if err := p.shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown returned error: %v", err)
+	}
+	if got := h.count(); got != 3 {
+		t.Fatalf("expected all 3 queued messages to be drained, got %d", got)
+	}
+
+	// A submit racing with (or arriving after) shutdown must not panic by
+	// sending on the now-closed queue channel.
+	p.submit(TextMessage{Text: "late"})
+	if got := h.count(); got != 3 {
+		t.Fatalf("expected the post-shutdown submit to be dropped, got %d messages", got)
+	}
+}
+
+func TestDispatchToHandlerDeliversSynchronously(t *testing.T) {
+	h := &blockingHandler{release: make(chan struct{})}
+	close(h.release)
+
+	dispatchToHandler(TextMessage{Text: "hi"}, h)
+
+	if got := h.count(); got != 1 {
+		t.Fatalf("expected dispatchToHandler to deliver synchronously, got %d messages", got)
+	}
+}
+
+type rawRecordingHandler struct {
+	n int
+}
+
+func (h *rawRecordingHandler) HandleError(err error)                    {}
+func (h *rawRecordingHandler) HandleRawMessage(m *proto.WebMessageInfo) { h.n++ }
+
+func TestDispatchToHandlerDeliversRawMessage(t *testing.T) {
+	h := &rawRecordingHandler{}
+	dispatchToHandler(&proto.WebMessageInfo{}, h)
+
+	if h.n != 1 {
+		t.Fatalf("expected HandleRawMessage to be called once, got %d", h.n)
+	}
+}
+
+func TestDispatchToHandlerReDispatchesEventMessageContent(t *testing.T) {
+	h := &blockingHandler{release: make(chan struct{})}
+	close(h.release)
+
+	dispatchToHandler(events.Message{Content: TextMessage{Text: "hi"}}, h)
+
+	if got := h.texts(); len(got) != 1 || got[0] != "hi" {
+		t.Fatalf("expected events.Message's Content to reach HandleTextMessage, got %v", got)
+	}
+}
+
+func TestDispatchToHandlerDeliversLifecycleEvents(t *testing.T) {
+	h := &eventRecordingHandler{}
+
+	dispatchToHandler(events.Connected{}, h)
+	dispatchToHandler(events.Disconnected{}, h)
+	dispatchToHandler(events.Reconnecting{Attempt: 1}, h)
+
+	if got := h.count(); got != 3 {
+		t.Fatalf("expected all 3 lifecycle events to reach HandleEvent, got %d", got)
+	}
+}
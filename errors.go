@@ -0,0 +1,25 @@
+package whatsapp
+
+import "fmt"
+
+// ErrConnectionFailed is returned when the initial dial to the WhatsApp socket fails.
+type ErrConnectionFailed struct {
+	Err error
+}
+
+func (e *ErrConnectionFailed) Error() string {
+	return fmt.Sprintf("connection failed: %v", e.Err)
+}
+
+// ErrConnectionClosed is returned when an established connection is closed unexpectedly.
+type ErrConnectionClosed struct {
+	Code int
+	Text string
+}
+
+func (e *ErrConnectionClosed) Error() string {
+	return fmt.Sprintf("connection closed: %v %v", e.Code, e.Text)
+}
+
+// ErrConnectionTimeout is returned when no data is received before the configured timeout elapses.
+var ErrConnectionTimeout = fmt.Errorf("connection timed out")
@@ -0,0 +1,92 @@
+/*
+Package events defines the typed events emitted by the multi-device Conn backend.
+
+Unlike the legacy binary.Node/proto.WebMessageInfo dispatch, these events are
+backend-agnostic: they are delivered to any whatsapp.EventHandler and, where a
+legacy equivalent exists (Message), also adapted onto the existing
+TextMessageHandler/ImageMessageHandler/etc. family so old handler code keeps
+compiling and working unmodified against the new backend.
+*/
+package events
+
+import "time"
+
+/*
+MessageInfo carries the envelope fields common to every incoming message,
+independent of its content type.
+*/
+type MessageInfo struct {
+	ID        string
+	Chat      string
+	Sender    string
+	IsFromMe  bool
+	Timestamp time.Time
+}
+
+/*
+Message is emitted for every incoming chat message on the multi-device backend.
+Content holds one of the whatsapp package's existing message types (TextMessage,
+ImageMessage, VideoMessage, ...) so it can be re-dispatched through the legacy
+handler interfaces.
+*/
+type Message struct {
+	Info    MessageInfo
+	Content interface{}
+}
+
+// ReceiptType identifies what kind of delivery receipt a Receipt event reports.
+type ReceiptType string
+
+const (
+	ReceiptDelivered ReceiptType = "delivered"
+	ReceiptRead      ReceiptType = "read"
+	ReceiptPlayed    ReceiptType = "played"
+)
+
+// Receipt is emitted when a delivery or read receipt arrives for a sent message.
+type Receipt struct {
+	MessageIDs []string
+	Chat       string
+	Sender     string
+	Type       ReceiptType
+	Timestamp  time.Time
+}
+
+// PresenceState identifies a contact's availability or composing state.
+type PresenceState string
+
+const (
+	PresenceAvailable   PresenceState = "available"
+	PresenceUnavailable PresenceState = "unavailable"
+	PresenceComposing   PresenceState = "composing"
+	PresenceRecording   PresenceState = "recording"
+	PresencePaused      PresenceState = "paused"
+)
+
+// Presence is emitted when a contact's availability or composing state changes.
+type Presence struct {
+	JID      string
+	State    PresenceState
+	LastSeen time.Time
+}
+
+// HistorySync is emitted when the phone pushes a backfill of chat history after pairing.
+type HistorySync struct {
+	SyncType string
+	Chats    int
+	Progress int
+}
+
+// Connected is emitted once an automatic reconnect by Conn.SetAutoReconnect has brought the connection back up.
+type Connected struct{}
+
+// Disconnected is emitted, while Conn.SetAutoReconnect is enabled, when a connection-class error arrives, before any reconnect attempt.
+type Disconnected struct {
+	Reason error
+}
+
+// Reconnecting is emitted once per automatic reconnect attempt made by Conn.SetAutoReconnect.
+type Reconnecting struct {
+	Attempt int
+	Backoff time.Duration
+}
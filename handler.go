@@ -6,6 +6,7 @@ import (
 
 	"github.com/gleandroj/go-whatsapp/binary"
 	"github.com/gleandroj/go-whatsapp/binary/proto"
+	"github.com/gleandroj/go-whatsapp/events"
 )
 
 /*
@@ -95,6 +96,53 @@ type RawMessageHandler interface {
 	HandleRawMessage(message *proto.WebMessageInfo)
 }
 
+/*
+The CallMessageHandler interface needs to be implemented to receive VoIP call offers,
+accepts, rejects and timeouts dispatched by the dispatcher.
+*/
+type CallMessageHandler interface {
+	Handler
+	HandleCallMessage(message CallMessage)
+}
+
+/*
+The ContactMessageHandler interface needs to be implemented to receive shared vCard
+contact messages dispatched by the dispatcher.
+*/
+type ContactMessageHandler interface {
+	Handler
+	HandleContactMessage(message ContactMessage)
+}
+
+/*
+The StickerMessageHandler interface needs to be implemented to receive sticker
+messages dispatched by the dispatcher.
+*/
+type StickerMessageHandler interface {
+	Handler
+	HandleStickerMessage(message StickerMessage)
+}
+
+/*
+The GroupInviteMessageHandler interface needs to be implemented to receive shared
+group-invite link messages dispatched by the dispatcher.
+*/
+type GroupInviteMessageHandler interface {
+	Handler
+	HandleGroupInviteMessage(message GroupInviteMessage)
+}
+
+/*
+EventHandler interface needs to be implemented to receive the backend-agnostic events
+(events.Message, events.Receipt, events.Presence, events.HistorySync, ...) emitted by the
+multi-device Backend. It exists alongside, not instead of, the legacy TextMessageHandler
+family: an events.Message is still re-dispatched through its matching handler above.
+*/
+type EventHandler interface {
+	Handler
+	HandleEvent(evt interface{})
+}
+
 /*
 AddHandler adds an handler to the list of handler that receive dispatched messages.
 The provided handler must at least implement the Handler interface. Additionally implemented
@@ -117,6 +165,9 @@ func handleMessage(message interface{}, handlers []Handler) {
 				go x.HandleJsonMessage(m)
 			}
 		}
+		if parsed := parseJsonMessage(m); parsed != nil {
+			dispatchJsonEvent(parsed, handlers)
+		}
 	case TextMessage:
 		for _, h := range handlers {
 			if x, ok := h.(TextMessageHandler); ok {
@@ -165,12 +216,71 @@ func handleMessage(message interface{}, handlers []Handler) {
 				go x.HandleRawMessage(m)
 			}
 		}
+	case CallMessage:
+		for _, h := range handlers {
+			if x, ok := h.(CallMessageHandler); ok {
+				go x.HandleCallMessage(m)
+			}
+		}
+	case ContactMessage:
+		for _, h := range handlers {
+			if x, ok := h.(ContactMessageHandler); ok {
+				go x.HandleContactMessage(m)
+			}
+		}
+	case StickerMessage:
+		for _, h := range handlers {
+			if x, ok := h.(StickerMessageHandler); ok {
+				go x.HandleStickerMessage(m)
+			}
+		}
+	case GroupInviteMessage:
+		for _, h := range handlers {
+			if x, ok := h.(GroupInviteMessageHandler); ok {
+				go x.HandleGroupInviteMessage(m)
+			}
+		}
+	case events.Message:
+		handleMessage(m.Content, handlers)
+		for _, h := range handlers {
+			if x, ok := h.(EventHandler); ok {
+				go x.HandleEvent(m)
+			}
+		}
+	case events.Receipt, events.Presence, events.HistorySync, events.Connected, events.Disconnected, events.Reconnecting:
+		for _, h := range handlers {
+			if x, ok := h.(EventHandler); ok {
+				go x.HandleEvent(m)
+			}
+		}
 	}
 
 }
 
+/*
+handle dispatches message to every registered handler. Handlers added through
+AddSyncHandler are routed to their worker pool (preserving order and applying
+back-pressure); handlers added through the plain AddHandler keep the legacy
+fire-and-forget `go` dispatch in handleMessage.
+*/
 func (wac *Conn) handle(message interface{}) {
-	handleMessage(message, wac.handler)
+	if err, ok := message.(error); ok && wac.autoReconnect != nil && isConnectionError(err) {
+		wac.handleConnectionError(err)
+		return
+	}
+
+	wac.syncPoolsMu.Lock()
+	async := make([]Handler, 0, len(wac.handler))
+	for _, h := range wac.handler {
+		if pool, ok := wac.syncPools[h]; ok {
+			pool.submit(message)
+			continue
+		}
+		async = append(async, h)
+	}
+	wac.syncPoolsMu.Unlock()
+
+	handleMessage(message, async)
 }
 
 func (wac *Conn) dispatch(msg interface{}) {
@@ -186,11 +296,16 @@ func (wac *Conn) dispatch(msg interface{}) {
 					if v, ok := con[a].(*proto.WebMessageInfo); ok {
 						wac.handle(v)
 						wac.handle(parseProtoMessage(v))
+						if extra := parseExtraProtoMessage(v); extra != nil {
+							wac.handle(extra)
+						}
 					}
 				}
 			}
 		} else if message.Description == "response" && message.Attributes["type"] == "contacts" {
 			wac.updateContacts(message.Content)
+		} else if message.Description == "call" {
+			wac.handle(parseCallMessage(message))
 		}
 	case error:
 		wac.handle(message)
@@ -200,3 +315,23 @@ func (wac *Conn) dispatch(msg interface{}) {
 		fmt.Fprintf(os.Stderr, "unknown type in dipatcher chan: %T", msg)
 	}
 }
+
+/*
+dispatchEvent is the multi-device counterpart of dispatch: it is fed from a
+Backend's Events() channel instead of the legacy binary.Node read loop. It goes
+through the same wac.handle as every other message so an events.Message is still
+re-dispatched onto the legacy TextMessageHandler/etc. family (handleMessage's
+events.Message case) and every event also reaches any registered EventHandler.
+*/
+func (wac *Conn) dispatchEvent(evt interface{}) {
+	if evt == nil {
+		return
+	}
+
+	switch evt.(type) {
+	case events.Message, events.Receipt, events.Presence, events.HistorySync, events.Connected, events.Disconnected, events.Reconnecting:
+		wac.handle(evt)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown type in event chan: %T", evt)
+	}
+}
@@ -0,0 +1,224 @@
+package whatsapp
+
+import "encoding/json"
+
+/*
+JsonPresence is the parsed form of a presence status frame: a contact going
+online/offline or starting/stopping composing, recording or a paused typing state.
+*/
+type JsonPresence struct {
+	Jid      string `json:"jid"`
+	Status   string `json:"type"`
+	LastSeen int64  `json:"t"`
+}
+
+// JsonReceipt is the parsed form of a delivered/read/played receipt frame.
+type JsonReceipt struct {
+	MessageIds []string `json:"id"`
+	Jid        string   `json:"jid"`
+	Type       string   `json:"type"`
+	Timestamp  int64    `json:"t"`
+}
+
+// JsonChatUpdate is the parsed form of a chat mute/archive/pin/clear/delete action frame.
+type JsonChatUpdate struct {
+	Jid    string `json:"jid"`
+	Action string `json:"cmd"`
+}
+
+// JsonStreamUpdate is the parsed form of a connecting/connected/disconnected stream state frame.
+type JsonStreamUpdate struct {
+	State  string `json:"state"`
+	Reason string `json:"reason"`
+}
+
+// JsonProps is the parsed form of the server-supplied feature-flag frame.
+type JsonProps struct {
+	Props map[string]string `json:"props"`
+}
+
+// JsonBattery is the parsed form of the phone's battery status frame.
+type JsonBattery struct {
+	Percentage int  `json:"value"`
+	Plugged    bool `json:"plugged"`
+	Powersave  bool `json:"powersave"`
+}
+
+/*
+The PresenceHandler interface needs to be implemented to receive parsed presence
+updates dispatched from the JSON message stream.
+*/
+type PresenceHandler interface {
+	Handler
+	HandlePresence(presence JsonPresence)
+}
+
+/*
+The ReceiptHandler interface needs to be implemented to receive parsed delivery/read
+receipts dispatched from the JSON message stream.
+*/
+type ReceiptHandler interface {
+	Handler
+	HandleReceipt(receipt JsonReceipt)
+}
+
+/*
+The ChatHandler interface needs to be implemented to receive parsed chat actions
+(mute, archive, pin, clear, delete) dispatched from the JSON message stream.
+*/
+type ChatHandler interface {
+	Handler
+	HandleChatUpdate(update JsonChatUpdate)
+}
+
+/*
+The StreamHandler interface needs to be implemented to receive parsed connection
+stream state changes dispatched from the JSON message stream.
+*/
+type StreamHandler interface {
+	Handler
+	HandleStreamUpdate(update JsonStreamUpdate)
+}
+
+/*
+The PropsHandler interface needs to be implemented to receive the server-supplied
+feature-flag frame dispatched from the JSON message stream.
+*/
+type PropsHandler interface {
+	Handler
+	HandleProps(props JsonProps)
+}
+
+/*
+The BatteryHandler interface needs to be implemented to receive parsed phone battery
+status updates dispatched from the JSON message stream.
+*/
+type BatteryHandler interface {
+	Handler
+	HandleBattery(battery JsonBattery)
+}
+
+/*
+parseJsonMessage parses one JSON frame off the WhatsApp Web socket, shaped as a
+two-element array of [tag, payload]. It returns nil if raw is not one of the frame
+types this package knows how to parse into a typed event, so callers should keep
+dispatching the raw string to JsonMessageHandler regardless of the result.
+*/
+func parseJsonMessage(raw string) interface{} {
+	var frame []json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &frame); err != nil || len(frame) < 2 {
+		return nil
+	}
+
+	var tag string
+	if err := json.Unmarshal(frame[0], &tag); err != nil {
+		return nil
+	}
+
+	switch tag {
+	case "Presence":
+		var p JsonPresence
+		if json.Unmarshal(frame[1], &p) == nil {
+			return p
+		}
+	case "Msg", "receipt":
+		var r JsonReceipt
+		if json.Unmarshal(frame[1], &r) == nil {
+			return r
+		}
+	case "Chat":
+		var c JsonChatUpdate
+		if json.Unmarshal(frame[1], &c) == nil {
+			return c
+		}
+	case "Stream":
+		var s JsonStreamUpdate
+		if json.Unmarshal(frame[1], &s) == nil {
+			return s
+		}
+	case "Props":
+		var p JsonProps
+		if json.Unmarshal(frame[1], &p) == nil {
+			return p
+		}
+	case "Battery":
+		var b JsonBattery
+		if json.Unmarshal(frame[1], &b) == nil {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// dispatchJsonEvent fans a parsed JSON frame out to its matching typed handler interface.
+func dispatchJsonEvent(evt interface{}, handlers []Handler) {
+	switch e := evt.(type) {
+	case JsonPresence:
+		for _, h := range handlers {
+			if x, ok := h.(PresenceHandler); ok {
+				go x.HandlePresence(e)
+			}
+		}
+	case JsonReceipt:
+		for _, h := range handlers {
+			if x, ok := h.(ReceiptHandler); ok {
+				go x.HandleReceipt(e)
+			}
+		}
+	case JsonChatUpdate:
+		for _, h := range handlers {
+			if x, ok := h.(ChatHandler); ok {
+				go x.HandleChatUpdate(e)
+			}
+		}
+	case JsonStreamUpdate:
+		for _, h := range handlers {
+			if x, ok := h.(StreamHandler); ok {
+				go x.HandleStreamUpdate(e)
+			}
+		}
+	case JsonProps:
+		for _, h := range handlers {
+			if x, ok := h.(PropsHandler); ok {
+				go x.HandleProps(e)
+			}
+		}
+	case JsonBattery:
+		for _, h := range handlers {
+			if x, ok := h.(BatteryHandler); ok {
+				go x.HandleBattery(e)
+			}
+		}
+	}
+}
+
+// dispatchJsonEventSync is the synchronous counterpart of dispatchJsonEvent, used by dispatchToHandler.
+func dispatchJsonEventSync(evt interface{}, h Handler) {
+	switch e := evt.(type) {
+	case JsonPresence:
+		if x, ok := h.(PresenceHandler); ok {
+			x.HandlePresence(e)
+		}
+	case JsonReceipt:
+		if x, ok := h.(ReceiptHandler); ok {
+			x.HandleReceipt(e)
+		}
+	case JsonChatUpdate:
+		if x, ok := h.(ChatHandler); ok {
+			x.HandleChatUpdate(e)
+		}
+	case JsonStreamUpdate:
+		if x, ok := h.(StreamHandler); ok {
+			x.HandleStreamUpdate(e)
+		}
+	case JsonProps:
+		if x, ok := h.(PropsHandler); ok {
+			x.HandleProps(e)
+		}
+	case JsonBattery:
+		if x, ok := h.(BatteryHandler); ok {
+			x.HandleBattery(e)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package whatsapp
+
+import "testing"
+
+func TestParseJsonMessagePresence(t *testing.T) {
+	raw := `["Presence", {"jid": "123@s.whatsapp.net", "type": "composing", "t": 42}]`
+	got, ok := parseJsonMessage(raw).(JsonPresence)
+	if !ok {
+		t.Fatalf("expected JsonPresence, got %#v", parseJsonMessage(raw))
+	}
+	want := JsonPresence{Jid: "123@s.whatsapp.net", Status: "composing", LastSeen: 42}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseJsonMessageReceipt(t *testing.T) {
+	for _, tag := range []string{"Msg", "receipt"} {
+		raw := `["` + tag + `", {"id": ["ABC", "DEF"], "jid": "123@s.whatsapp.net", "type": "read", "t": 7}]`
+		got, ok := parseJsonMessage(raw).(JsonReceipt)
+		if !ok {
+			t.Fatalf("tag %q: expected JsonReceipt, got %#v", tag, parseJsonMessage(raw))
+		}
+		if got.Jid != "123@s.whatsapp.net" || got.Type != "read" || got.Timestamp != 7 || len(got.MessageIds) != 2 {
+			t.Fatalf("tag %q: unexpected JsonReceipt: %+v", tag, got)
+		}
+	}
+}
+
+func TestParseJsonMessageChatUpdate(t *testing.T) {
+	raw := `["Chat", {"jid": "123@s.whatsapp.net", "cmd": "archive"}]`
+	got, ok := parseJsonMessage(raw).(JsonChatUpdate)
+	if !ok {
+		t.Fatalf("expected JsonChatUpdate, got %#v", parseJsonMessage(raw))
+	}
+	want := JsonChatUpdate{Jid: "123@s.whatsapp.net", Action: "archive"}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseJsonMessageStreamUpdate(t *testing.T) {
+	raw := `["Stream", {"state": "CLOSE", "reason": "replaced"}]`
+	got, ok := parseJsonMessage(raw).(JsonStreamUpdate)
+	if !ok {
+		t.Fatalf("expected JsonStreamUpdate, got %#v", parseJsonMessage(raw))
+	}
+	want := JsonStreamUpdate{State: "CLOSE", Reason: "replaced"}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseJsonMessageProps(t *testing.T) {
+	raw := `["Props", {"props": {"GROUPS_V4": "1"}}]`
+	got, ok := parseJsonMessage(raw).(JsonProps)
+	if !ok {
+		t.Fatalf("expected JsonProps, got %#v", parseJsonMessage(raw))
+	}
+	if got.Props["GROUPS_V4"] != "1" {
+		t.Fatalf("unexpected JsonProps: %+v", got)
+	}
+}
+
+func TestParseJsonMessageBattery(t *testing.T) {
+	raw := `["Battery", {"value": 80, "plugged": true, "powersave": false}]`
+	got, ok := parseJsonMessage(raw).(JsonBattery)
+	if !ok {
+		t.Fatalf("expected JsonBattery, got %#v", parseJsonMessage(raw))
+	}
+	want := JsonBattery{Percentage: 80, Plugged: true, Powersave: false}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseJsonMessageUnknownTagReturnsNil(t *testing.T) {
+	if got := parseJsonMessage(`["SomethingElse", {}]`); got != nil {
+		t.Fatalf("expected nil for an unrecognized tag, got %#v", got)
+	}
+}
+
+func TestParseJsonMessageMalformedInput(t *testing.T) {
+	for _, raw := range []string{"not json", "{}", `["Presence"]`, `["Presence", "not an object"]`} {
+		if got := parseJsonMessage(raw); got != nil {
+			t.Fatalf("raw %q: expected nil, got %#v", raw, got)
+		}
+	}
+}
+
+type presenceRecordingHandler struct {
+	last JsonPresence
+	n    int
+}
+
+func (h *presenceRecordingHandler) HandleError(err error)         {}
+func (h *presenceRecordingHandler) HandlePresence(p JsonPresence) { h.last = p; h.n++ }
+
+func TestDispatchJsonEventSyncDeliversToMatchingHandler(t *testing.T) {
+	h := &presenceRecordingHandler{}
+	dispatchJsonEventSync(JsonPresence{Jid: "abc", Status: "available"}, h)
+
+	if h.n != 1 || h.last.Jid != "abc" {
+		t.Fatalf("expected HandlePresence to be called once with the parsed presence, got %+v (n=%d)", h.last, h.n)
+	}
+}
+
+func TestDispatchJsonEventSyncIgnoresNonMatchingHandler(t *testing.T) {
+	h := &blockingHandler{release: make(chan struct{})}
+	close(h.release)
+
+	// blockingHandler implements TextMessageHandler, not PresenceHandler, so this
+	// must be a silent no-op rather than a panic or type assertion failure.
+	dispatchJsonEventSync(JsonPresence{Jid: "abc"}, h)
+
+	if h.count() != 0 {
+		t.Fatalf("expected no messages delivered to a handler that doesn't implement PresenceHandler")
+	}
+}
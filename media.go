@@ -0,0 +1,223 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// HKDF info strings used to derive the iv/cipher/MAC keys for each media type, per
+// WhatsApp's media encryption scheme.
+const (
+	mediaInfoImage    = "WhatsApp Image Keys"
+	mediaInfoVideo    = "WhatsApp Video Keys"
+	mediaInfoAudio    = "WhatsApp Audio Keys"
+	mediaInfoDocument = "WhatsApp Document Keys"
+)
+
+// mediaMACSize is the length, in bytes, of the truncated HMAC-SHA256 WhatsApp
+// appends to the end of an encrypted media blob.
+const mediaMACSize = 10
+
+// mediaReadSize is how much ciphertext downloadEncrypted reads from the network
+// per iteration. It bounds the function's memory use to a small multiple of this,
+// not the size of the file being downloaded.
+const mediaReadSize = 32 * 1024
+
+// deriveMediaKeys expands a 32-byte MediaKey into the iv/cipherKey/macKey used to
+// decrypt and verify a downloaded media blob.
+func deriveMediaKeys(mediaKey []byte, info string) (iv, cipherKey, macKey []byte, err error) {
+	expander := hkdf.New(sha256.New, mediaKey, nil, []byte(info))
+	out := make([]byte, 112)
+	if _, err := io.ReadFull(expander, out); err != nil {
+		return nil, nil, nil, err
+	}
+	return out[:16], out[16:48], out[48:80], nil
+}
+
+/*
+downloadEncrypted streams url's body, AES-CBC-decrypting it into w as it arrives
+and validating the trailing mediaMACSize-byte HMAC-SHA256(iv || ciphertext)
+against the MAC key derived from mediaKey. Memory use is bounded by
+mediaReadSize, not by the size of the file: the last mediaMACSize bytes read (plus
+fewer than aes.BlockSize bytes that do not yet fill a block) are always held back
+undecrypted, since they might turn out to be the MAC rather than ciphertext, and
+the most recently decrypted chunk is held back one more iteration so its PKCS7
+padding can only be stripped once the MAC confirms it is genuinely the final chunk.
+*/
+func downloadEncrypted(ctx context.Context, url string, mediaKey []byte, info string, w io.Writer) error {
+	iv, cipherKey, macKey, err := deriveMediaKeys(mediaKey, info)
+	if err != nil {
+		return err
+	}
+
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return err
+	}
+	mode := cipher.NewCBCDecrypter(block, iv)
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(iv)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("whatsapp: media download failed with status %v", resp.Status)
+	}
+
+	var pending, lastPlain []byte
+	readBuf := make([]byte, mediaReadSize)
+
+	for {
+		n, readErr := resp.Body.Read(readBuf)
+		if n > 0 {
+			pending = append(pending, readBuf[:n]...)
+
+			committable := len(pending) - mediaMACSize
+			committable -= committable % aes.BlockSize
+			if committable > 0 {
+				chunk := pending[:committable]
+				mac.Write(chunk)
+
+				plain := make([]byte, len(chunk))
+				mode.CryptBlocks(plain, chunk)
+
+				if lastPlain != nil {
+					if _, err := w.Write(lastPlain); err != nil {
+						return err
+					}
+				}
+				lastPlain = plain
+
+				pending = pending[committable:]
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	if len(pending) != mediaMACSize {
+		return errors.New("whatsapp: media download is not block-aligned")
+	}
+	if !hmac.Equal(mac.Sum(nil)[:mediaMACSize], pending) {
+		return errors.New("whatsapp: media MAC validation failed")
+	}
+
+	if lastPlain == nil {
+		return nil
+	}
+	_, err = w.Write(pkcs7Unpad(lastPlain))
+	return err
+}
+
+func pkcs7Unpad(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	pad := int(data[len(data)-1])
+	if pad <= 0 || pad > len(data) {
+		return data
+	}
+	return data[:len(data)-pad]
+}
+
+// Download fetches and decrypts m's image in one call, buffering it in memory.
+func (m ImageMessage) Download(ctx context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := m.DownloadTo(ctx, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DownloadTo streams and decrypts m's image into w without buffering the whole file.
+func (m ImageMessage) DownloadTo(ctx context.Context, w io.Writer) error {
+	return downloadEncrypted(ctx, m.Url, m.MediaKey, mediaInfoImage, w)
+}
+
+// Thumbnail returns the already-decrypted JPEG thumbnail embedded in the message.
+func (m ImageMessage) Thumbnail() ([]byte, error) {
+	return thumbnailOrErr(m.ThumbnailData)
+}
+
+// Download fetches and decrypts m's video in one call, buffering it in memory.
+func (m VideoMessage) Download(ctx context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := m.DownloadTo(ctx, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DownloadTo streams and decrypts m's video into w without buffering the whole file.
+func (m VideoMessage) DownloadTo(ctx context.Context, w io.Writer) error {
+	return downloadEncrypted(ctx, m.Url, m.MediaKey, mediaInfoVideo, w)
+}
+
+// Thumbnail returns the already-decrypted JPEG thumbnail embedded in the message.
+func (m VideoMessage) Thumbnail() ([]byte, error) {
+	return thumbnailOrErr(m.ThumbnailData)
+}
+
+// Download fetches and decrypts m's audio in one call, buffering it in memory.
+func (m AudioMessage) Download(ctx context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := m.DownloadTo(ctx, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DownloadTo streams and decrypts m's audio into w without buffering the whole file.
+func (m AudioMessage) DownloadTo(ctx context.Context, w io.Writer) error {
+	return downloadEncrypted(ctx, m.Url, m.MediaKey, mediaInfoAudio, w)
+}
+
+// Download fetches and decrypts m's document in one call, buffering it in memory.
+func (m DocumentMessage) Download(ctx context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := m.DownloadTo(ctx, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DownloadTo streams and decrypts m's document into w without buffering the whole file.
+func (m DocumentMessage) DownloadTo(ctx context.Context, w io.Writer) error {
+	return downloadEncrypted(ctx, m.Url, m.MediaKey, mediaInfoDocument, w)
+}
+
+// Thumbnail returns the already-decrypted JPEG thumbnail embedded in the message.
+func (m DocumentMessage) Thumbnail() ([]byte, error) {
+	return thumbnailOrErr(m.ThumbnailData)
+}
+
+func thumbnailOrErr(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("whatsapp: message has no thumbnail")
+	}
+	return data, nil
+}
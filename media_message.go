@@ -0,0 +1,53 @@
+package whatsapp
+
+// ImageMessage represents an image attachment.
+type ImageMessage struct {
+	Info          MessageInfo
+	Caption       string
+	ThumbnailData []byte
+	Type          string
+	Url           string
+	MediaKey      []byte
+	FileEncSha256 []byte
+	FileSha256    []byte
+	FileLength    uint64
+}
+
+// VideoMessage represents a video attachment.
+type VideoMessage struct {
+	Info          MessageInfo
+	Caption       string
+	ThumbnailData []byte
+	Type          string
+	Url           string
+	MediaKey      []byte
+	FileEncSha256 []byte
+	FileSha256    []byte
+	FileLength    uint64
+}
+
+// AudioMessage represents an audio attachment, including voice notes.
+type AudioMessage struct {
+	Info          MessageInfo
+	ThumbnailData []byte
+	Type          string
+	Url           string
+	MediaKey      []byte
+	FileEncSha256 []byte
+	FileSha256    []byte
+	FileLength    uint64
+}
+
+// DocumentMessage represents a document/file attachment.
+type DocumentMessage struct {
+	Info          MessageInfo
+	Title         string
+	FileName      string
+	ThumbnailData []byte
+	Type          string
+	Url           string
+	MediaKey      []byte
+	FileEncSha256 []byte
+	FileSha256    []byte
+	FileLength    uint64
+}
@@ -0,0 +1,154 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// encryptMedia builds a blob shaped like a real WhatsApp media download: AES-CBC
+// ciphertext followed by a truncated HMAC-SHA256(iv||ciphertext), using the same
+// key derivation downloadEncrypted uses to decrypt it. It exists only to give these
+// tests a fixture; it is not meant to be an independent reimplementation.
+func encryptMedia(t *testing.T, plain, mediaKey []byte, info string) []byte {
+	t.Helper()
+
+	iv, cipherKey, macKey, err := deriveMediaKeys(mediaKey, info)
+	if err != nil {
+		t.Fatalf("deriveMediaKeys: %v", err)
+	}
+
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		t.Fatalf("aes.NewCipher: %v", err)
+	}
+
+	pad := aes.BlockSize - len(plain)%aes.BlockSize
+	padded := make([]byte, len(plain)+pad)
+	copy(padded, plain)
+	for i := len(plain); i < len(padded); i++ {
+		padded[i] = byte(pad)
+	}
+
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+
+	return append(ciphertext, mac.Sum(nil)[:mediaMACSize]...)
+}
+
+func serveBlob(blob []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(blob)
+	}))
+}
+
+func TestDownloadEncryptedRoundTrip(t *testing.T) {
+	mediaKey := make([]byte, 32)
+	if _, err := rand.Read(mediaKey); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+
+	// Large enough to span several mediaReadSize chunks, so the streaming
+	// accumulate/commit logic in downloadEncrypted actually gets exercised.
+	want := bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog. "), 5000)
+
+	blob := encryptMedia(t, want, mediaKey, mediaInfoImage)
+	srv := serveBlob(blob)
+	defer srv.Close()
+
+	img := ImageMessage{Url: srv.URL, MediaKey: mediaKey}
+	got, err := img.Download(context.Background())
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("decrypted content mismatch: got %d bytes, want %d bytes", len(got), len(want))
+	}
+}
+
+func TestDownloadEncryptedEmptyPayload(t *testing.T) {
+	mediaKey := make([]byte, 32)
+	if _, err := rand.Read(mediaKey); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+
+	blob := encryptMedia(t, nil, mediaKey, mediaInfoDocument)
+	srv := serveBlob(blob)
+	defer srv.Close()
+
+	doc := DocumentMessage{Url: srv.URL, MediaKey: mediaKey}
+	got, err := doc.Download(context.Background())
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected empty content, got %d bytes", len(got))
+	}
+}
+
+func TestDownloadEncryptedBadMAC(t *testing.T) {
+	mediaKey := make([]byte, 32)
+	if _, err := rand.Read(mediaKey); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+
+	blob := encryptMedia(t, []byte("tampered payload"), mediaKey, mediaInfoAudio)
+	blob[len(blob)-1] ^= 0xFF // corrupt the trailing MAC byte
+
+	srv := serveBlob(blob)
+	defer srv.Close()
+
+	aud := AudioMessage{Url: srv.URL, MediaKey: mediaKey}
+	if _, err := aud.Download(context.Background()); err == nil {
+		t.Fatal("expected MAC validation to fail, got nil error")
+	}
+}
+
+func TestDownloadEncryptedWrongKey(t *testing.T) {
+	mediaKey := make([]byte, 32)
+	if _, err := rand.Read(mediaKey); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+	wrongKey := make([]byte, 32)
+	if _, err := rand.Read(wrongKey); err != nil {
+		t.Fatalf("rand.Read: %v", err)
+	}
+
+	blob := encryptMedia(t, []byte("only decryptable with the right key"), mediaKey, mediaInfoVideo)
+	srv := serveBlob(blob)
+	defer srv.Close()
+
+	vid := VideoMessage{Url: srv.URL, MediaKey: wrongKey}
+	if _, err := vid.Download(context.Background()); err == nil {
+		t.Fatal("expected MAC validation to fail with the wrong MediaKey, got nil error")
+	}
+}
+
+func TestThumbnailMissing(t *testing.T) {
+	img := ImageMessage{}
+	if _, err := img.Thumbnail(); err == nil {
+		t.Fatal("expected an error when no thumbnail is embedded")
+	}
+}
+
+func TestThumbnailPresent(t *testing.T) {
+	img := ImageMessage{ThumbnailData: []byte("jpeg bytes")}
+	got, err := img.Thumbnail()
+	if err != nil {
+		t.Fatalf("Thumbnail: %v", err)
+	}
+	if !bytes.Equal(got, img.ThumbnailData) {
+		t.Fatalf("got %v, want %v", got, img.ThumbnailData)
+	}
+}
@@ -0,0 +1,56 @@
+package whatsapp
+
+import "github.com/gleandroj/go-whatsapp/binary/proto"
+
+/*
+MessageInfo carries the envelope fields common to every message parsed out of a
+WebMessageInfo, independent of its content type.
+*/
+type MessageInfo struct {
+	Id        string
+	RemoteJid string
+	SenderJid string
+	FromMe    bool
+	Timestamp uint64
+	PushName  string
+}
+
+// getMessageInfo builds a MessageInfo from the envelope fields of a WebMessageInfo.
+func getMessageInfo(info *proto.WebMessageInfo) MessageInfo {
+	key := info.GetKey()
+	return MessageInfo{
+		Id:        key.GetId(),
+		RemoteJid: key.GetRemoteJid(),
+		SenderJid: info.GetParticipant(),
+		FromMe:    key.GetFromMe(),
+		Timestamp: info.GetMessageTimestamp(),
+		PushName:  info.GetPushName(),
+	}
+}
+
+// TextMessage represents a plain text chat message.
+type TextMessage struct {
+	Info MessageInfo
+	Text string
+}
+
+// LocationMessage represents a one-off shared location.
+type LocationMessage struct {
+	Info      MessageInfo
+	Name      string
+	Address   string
+	Latitude  float64
+	Longitude float64
+	Thumbnail []byte
+}
+
+// LiveLocationMessage represents a location that updates in real time.
+type LiveLocationMessage struct {
+	Info             MessageInfo
+	Latitude         float64
+	Longitude        float64
+	AccuracyInMeters uint32
+	SpeedInMps       float32
+	SequenceNumber   int64
+	Thumbnail        []byte
+}
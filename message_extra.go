@@ -0,0 +1,127 @@
+package whatsapp
+
+import (
+	"github.com/gleandroj/go-whatsapp/binary"
+	"github.com/gleandroj/go-whatsapp/binary/proto"
+)
+
+// CallState identifies the lifecycle state of an incoming VoIP call.
+type CallState int
+
+const (
+	CallOffer CallState = iota
+	CallAccept
+	CallReject
+	CallTimeout
+)
+
+/*
+CallMessage represents a VoIP call offer or termination. It is dispatched from the
+"call" stanza rather than from a WebMessageInfo, so it carries the caller JID
+directly instead of a MessageInfo.
+*/
+type CallMessage struct {
+	CallID string
+	From   string
+	State  CallState
+	Video  bool
+}
+
+// ContactMessage represents a shared vCard contact.
+type ContactMessage struct {
+	Info        MessageInfo
+	DisplayName string
+	Vcard       string
+}
+
+// StickerMessage represents a WebP sticker, static or animated.
+type StickerMessage struct {
+	Info     MessageInfo
+	Url      string
+	MediaKey []byte
+	Type     string
+	Animated bool
+}
+
+// GroupInviteMessage represents a shared group-invite link.
+type GroupInviteMessage struct {
+	Info       MessageInfo
+	GroupJID   string
+	GroupName  string
+	Code       string
+	Expiration int64
+}
+
+/*
+parseExtraProtoMessage extracts ContactMessage, StickerMessage and GroupInviteMessage
+content from a WebMessageInfo. Conn.dispatch calls it right after parseProtoMessage,
+which handles the existing text/media/location types, so all these message families
+share the same dispatcher entry point. It returns nil if info does not carry one of
+these types.
+*/
+func parseExtraProtoMessage(info *proto.WebMessageInfo) interface{} {
+	msg := info.GetMessage()
+
+	switch {
+	case msg.GetContactMessage() != nil:
+		c := msg.GetContactMessage()
+		return ContactMessage{
+			Info:        getMessageInfo(info),
+			DisplayName: c.GetDisplayName(),
+			Vcard:       c.GetVcard(),
+		}
+	case msg.GetStickerMessage() != nil:
+		s := msg.GetStickerMessage()
+		return StickerMessage{
+			Info:     getMessageInfo(info),
+			Url:      s.GetUrl(),
+			MediaKey: s.GetMediaKey(),
+			Type:     s.GetMimetype(),
+			Animated: s.GetIsAnimated(),
+		}
+	case msg.GetGroupInviteMessage() != nil:
+		g := msg.GetGroupInviteMessage()
+		return GroupInviteMessage{
+			Info:       getMessageInfo(info),
+			GroupJID:   g.GetGroupJid(),
+			GroupName:  g.GetGroupName(),
+			Code:       g.GetInviteCode(),
+			Expiration: g.GetInviteExpiration(),
+		}
+	}
+
+	return nil
+}
+
+/*
+parseCallMessage extracts a CallMessage from a "call" stanza. Offers, accepts,
+rejects and timeouts are all delivered as the same node tag, distinguished by the
+node's Content entries.
+*/
+func parseCallMessage(node *binary.Node) CallMessage {
+	call := CallMessage{
+		CallID: node.Attributes["id"],
+		From:   node.Attributes["from"],
+		State:  CallOffer,
+	}
+
+	if con, ok := node.Content.([]interface{}); ok {
+		for _, c := range con {
+			if child, ok := c.(*binary.Node); ok {
+				switch child.Description {
+				case "offer":
+					call.State = CallOffer
+					call.Video = child.Attributes["video"] == "1"
+				case "accept":
+					call.State = CallAccept
+				case "reject":
+					call.State = CallReject
+				case "timeout":
+					call.State = CallTimeout
+				}
+			}
+		}
+	}
+
+	return call
+}
@@ -0,0 +1,132 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/gleandroj/go-whatsapp/binary"
+	"github.com/gleandroj/go-whatsapp/binary/proto"
+)
+
+func TestParseCallMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		childTag  string
+		videoAttr string
+		wantState CallState
+		wantVideo bool
+	}{
+		{name: "offer", childTag: "offer", wantState: CallOffer},
+		{name: "offer with video", childTag: "offer", videoAttr: "1", wantState: CallOffer, wantVideo: true},
+		{name: "accept", childTag: "accept", wantState: CallAccept},
+		{name: "reject", childTag: "reject", wantState: CallReject},
+		{name: "timeout", childTag: "timeout", wantState: CallTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			childAttrs := map[string]string{}
+			if tt.videoAttr != "" {
+				childAttrs["video"] = tt.videoAttr
+			}
+
+			node := &binary.Node{
+				Description: "call",
+				Attributes:  map[string]string{"id": "CALL1", "from": "123@s.whatsapp.net"},
+				Content: []interface{}{
+					&binary.Node{Description: tt.childTag, Attributes: childAttrs},
+				},
+			}
+
+			got := parseCallMessage(node)
+			if got.CallID != "CALL1" || got.From != "123@s.whatsapp.net" {
+				t.Fatalf("unexpected envelope fields: %+v", got)
+			}
+			if got.State != tt.wantState {
+				t.Fatalf("got state %v, want %v", got.State, tt.wantState)
+			}
+			if got.Video != tt.wantVideo {
+				t.Fatalf("got video %v, want %v", got.Video, tt.wantVideo)
+			}
+		})
+	}
+}
+
+func TestParseCallMessageDefaultsToOfferWithNoChildren(t *testing.T) {
+	node := &binary.Node{
+		Description: "call",
+		Attributes:  map[string]string{"id": "CALL2", "from": "456@s.whatsapp.net"},
+	}
+
+	got := parseCallMessage(node)
+	if got.State != CallOffer || got.Video {
+		t.Fatalf("unexpected default CallMessage: %+v", got)
+	}
+}
+
+func TestParseExtraProtoMessageContact(t *testing.T) {
+	info := &proto.WebMessageInfo{
+		Message: &proto.Message{
+			ContactMessage: &proto.ContactMessage{
+				DisplayName: "Jane Doe",
+				Vcard:       "BEGIN:VCARD...",
+			},
+		},
+	}
+
+	got, ok := parseExtraProtoMessage(info).(ContactMessage)
+	if !ok {
+		t.Fatalf("expected ContactMessage, got %#v", parseExtraProtoMessage(info))
+	}
+	if got.DisplayName != "Jane Doe" || got.Vcard != "BEGIN:VCARD..." {
+		t.Fatalf("unexpected ContactMessage: %+v", got)
+	}
+}
+
+func TestParseExtraProtoMessageSticker(t *testing.T) {
+	info := &proto.WebMessageInfo{
+		Message: &proto.Message{
+			StickerMessage: &proto.StickerMessage{
+				Url:        "https://example.test/sticker.webp",
+				MediaKey:   []byte{1, 2, 3},
+				Mimetype:   "image/webp",
+				IsAnimated: true,
+			},
+		},
+	}
+
+	got, ok := parseExtraProtoMessage(info).(StickerMessage)
+	if !ok {
+		t.Fatalf("expected StickerMessage, got %#v", parseExtraProtoMessage(info))
+	}
+	if got.Url != "https://example.test/sticker.webp" || got.Type != "image/webp" || !got.Animated {
+		t.Fatalf("unexpected StickerMessage: %+v", got)
+	}
+}
+
+func TestParseExtraProtoMessageGroupInvite(t *testing.T) {
+	info := &proto.WebMessageInfo{
+		Message: &proto.Message{
+			GroupInviteMessage: &proto.GroupInviteMessage{
+				GroupJid:         "123-456@g.us",
+				GroupName:        "Family",
+				InviteCode:       "abc123",
+				InviteExpiration: 1700000000,
+			},
+		},
+	}
+
+	got, ok := parseExtraProtoMessage(info).(GroupInviteMessage)
+	if !ok {
+		t.Fatalf("expected GroupInviteMessage, got %#v", parseExtraProtoMessage(info))
+	}
+	if got.GroupJID != "123-456@g.us" || got.Code != "abc123" || got.Expiration != 1700000000 {
+		t.Fatalf("unexpected GroupInviteMessage: %+v", got)
+	}
+}
+
+func TestParseExtraProtoMessageReturnsNilForEmptyMessage(t *testing.T) {
+	info := &proto.WebMessageInfo{Message: &proto.Message{}}
+	if got := parseExtraProtoMessage(info); got != nil {
+		t.Fatalf("expected nil for a message with none of the extra types set, got %#v", got)
+	}
+}
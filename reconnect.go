@@ -0,0 +1,148 @@
+package whatsapp
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gleandroj/go-whatsapp/events"
+)
+
+/*
+SessionProvider lets Conn load the Session on its own, so SetAutoReconnect can call
+RestoreWithSession without the caller wiring that up by hand inside HandleError.
+Conn calls only Load; it never calls Store.
+*/
+type SessionProvider interface {
+	Load() (Session, error)
+	Store(Session) error
+}
+
+// AutoReconnectConfig configures Conn's built-in reconnect-with-backoff loop.
+type AutoReconnectConfig struct {
+	Min    time.Duration
+	Max    time.Duration
+	Factor float64
+	Jitter bool
+
+	Session SessionProvider
+}
+
+type autoReconnect struct {
+	cfg AutoReconnectConfig
+
+	// mu guards attempt and active against the concurrent HandleError calls a real
+	// outage produces: without it, multiple reconnect loops could run at once,
+	// each racing the other on attempt and on Session.Load/RestoreWithSession.
+	mu      sync.Mutex
+	attempt int
+	active  bool
+}
+
+/*
+SetAutoReconnect enables automatic reconnect on connection-class errors
+(*ErrConnectionFailed, *ErrConnectionClosed, ErrConnectionTimeout). While enabled,
+those errors are swallowed from HandleError and surfaced instead as
+events.Disconnected/events.Reconnecting through any registered EventHandler, and
+cfg.Session is used to retry RestoreWithSession with exponential backoff.
+*/
+func (wac *Conn) SetAutoReconnect(cfg AutoReconnectConfig) {
+	if cfg.Min <= 0 {
+		cfg.Min = time.Second
+	}
+	if cfg.Max <= 0 {
+		cfg.Max = 30 * time.Second
+	}
+	if cfg.Factor <= 1 {
+		cfg.Factor = 2
+	}
+	wac.autoReconnect = &autoReconnect{cfg: cfg}
+}
+
+// isConnectionError reports whether err is one of the connection-class errors
+// that SetAutoReconnect handles on the caller's behalf instead of HandleError.
+func isConnectionError(err error) bool {
+	switch err.(type) {
+	case *ErrConnectionFailed, *ErrConnectionClosed:
+		return true
+	}
+	return err == ErrConnectionTimeout
+}
+
+/*
+handleConnectionError replaces the normal HandleError dispatch for connection-class
+errors once SetAutoReconnect is enabled. It emits events.Disconnected immediately,
+then retries RestoreWithSession with exponential backoff, emitting
+events.Reconnecting before each attempt and events.Connected once the socket is
+back up. Without a SessionProvider there is nothing to restore from, so it makes a
+single plain reconnect attempt instead of spinning Reconnecting forever.
+
+A real outage can fire HandleError repeatedly while a reconnect loop is already
+running, so ar.active single-flights the goroutine below: a call that finds one
+already active just reports Disconnected and returns.
+*/
+func (wac *Conn) handleConnectionError(err error) {
+	ar := wac.autoReconnect
+	wac.handle(events.Disconnected{Reason: err})
+
+	if ar.cfg.Session == nil {
+		wac.handle(events.Reconnecting{Attempt: 1, Backoff: 0})
+		if connErr := wac.connect(); connErr == nil {
+			wac.handle(events.Connected{})
+		}
+		return
+	}
+
+	ar.mu.Lock()
+	if ar.active {
+		ar.mu.Unlock()
+		return
+	}
+	ar.active = true
+	ar.mu.Unlock()
+
+	go func() {
+		defer func() {
+			ar.mu.Lock()
+			ar.active = false
+			ar.mu.Unlock()
+		}()
+
+		backoff := ar.cfg.Min
+		for {
+			ar.mu.Lock()
+			ar.attempt++
+			attempt := ar.attempt
+			ar.mu.Unlock()
+
+			wac.handle(events.Reconnecting{Attempt: attempt, Backoff: backoff})
+			time.Sleep(backoff)
+
+			if session, loadErr := ar.cfg.Session.Load(); loadErr == nil {
+				if _, restoreErr := wac.RestoreWithSession(session); restoreErr == nil {
+					ar.mu.Lock()
+					ar.attempt = 0
+					ar.mu.Unlock()
+					wac.handle(events.Connected{})
+					return
+				}
+			}
+
+			backoff = nextBackoff(backoff, ar.cfg)
+		}
+	}()
+}
+
+// nextBackoff advances backoff by cfg.Factor, clamps it to cfg.Max, and applies
+// jitter if cfg.Jitter is set. It is split out of handleConnectionError's loop so
+// the backoff/jitter math can be tested without driving a real reconnect loop.
+func nextBackoff(backoff time.Duration, cfg AutoReconnectConfig) time.Duration {
+	backoff = time.Duration(float64(backoff) * cfg.Factor)
+	if backoff > cfg.Max {
+		backoff = cfg.Max
+	}
+	if cfg.Jitter && backoff > 0 {
+		backoff = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+	}
+	return backoff
+}
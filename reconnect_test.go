@@ -0,0 +1,79 @@
+package whatsapp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextBackoffGrowsAndClampsToMax(t *testing.T) {
+	cfg := AutoReconnectConfig{Min: time.Second, Max: 4 * time.Second, Factor: 2}
+
+	got := nextBackoff(time.Second, cfg)
+	if got != 2*time.Second {
+		t.Fatalf("got %v, want %v", got, 2*time.Second)
+	}
+
+	got = nextBackoff(got, cfg)
+	if got != 4*time.Second {
+		t.Fatalf("got %v, want %v", got, 4*time.Second)
+	}
+
+	// Already at Max: one more step must clamp instead of overshooting to 8s.
+	got = nextBackoff(got, cfg)
+	if got != 4*time.Second {
+		t.Fatalf("got %v, want backoff clamped to Max (%v)", got, cfg.Max)
+	}
+}
+
+func TestNextBackoffJitterStaysInRange(t *testing.T) {
+	cfg := AutoReconnectConfig{Min: time.Second, Max: 10 * time.Second, Factor: 2, Jitter: true}
+
+	for i := 0; i < 50; i++ {
+		got := nextBackoff(2*time.Second, cfg)
+		if got < 2*time.Second || got > 4*time.Second {
+			t.Fatalf("jittered backoff %v out of expected [%v, %v] range", got, 2*time.Second, 4*time.Second)
+		}
+	}
+}
+
+// blockingSessionProvider lets a test hold a reconnect loop inside Session.Load()
+// so it can assert a second handleConnectionError call doesn't start a competing
+// loop while the first is still in flight.
+type blockingSessionProvider struct {
+	loadCalls chan struct{}
+	release   chan struct{}
+}
+
+func (s *blockingSessionProvider) Load() (Session, error) {
+	s.loadCalls <- struct{}{}
+	<-s.release
+	return Session{}, errors.New("session unavailable")
+}
+
+func (s *blockingSessionProvider) Store(Session) error { return nil }
+
+func TestHandleConnectionErrorSingleFlight(t *testing.T) {
+	wac := &Conn{}
+	sp := &blockingSessionProvider{loadCalls: make(chan struct{}, 10), release: make(chan struct{})}
+	wac.SetAutoReconnect(AutoReconnectConfig{Min: time.Millisecond, Max: time.Millisecond, Factor: 2, Session: sp})
+
+	wac.handleConnectionError(ErrConnectionTimeout)
+	select {
+	case <-sp.loadCalls:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the first reconnect loop to call Session.Load")
+	}
+
+	// A second connection-class error arriving while the first loop is parked
+	// inside Load must not start a competing loop.
+	wac.handleConnectionError(ErrConnectionTimeout)
+
+	select {
+	case <-sp.loadCalls:
+		t.Fatal("expected the second handleConnectionError call to be a no-op while a reconnect loop is active")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(sp.release)
+}
@@ -0,0 +1,16 @@
+package whatsapp
+
+/*
+Session holds everything needed to restore a previously established connection
+without going through QR/pair-code login again. It is returned by Login/PairQR and
+should be persisted by the caller (see SessionProvider) and passed back into
+RestoreWithSession on the next run.
+*/
+type Session struct {
+	ClientId    string
+	ClientToken string
+	ServerToken string
+	EncKey      []byte
+	MacKey      []byte
+	Wid         string
+}